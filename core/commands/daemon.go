@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/ipfs/go-ipfs/core"
+	"github.com/ipfs/go-ipfs/core/commands/pin"
+	"github.com/ipfs/go-ipfs/core/corehttp"
+	"github.com/ipfs/go-ipfs/core/corehttp/pinsvc"
+)
+
+// go-ipfs's daemon startup (node construction, the gateway, the commands
+// API, etc.) lives in cmd/ipfs/daemon.go, which is not part of this tree.
+// OnDaemonStart and DaemonServeOptions are the hooks this backlog adds for
+// that command to call once the node is online, rather than this package
+// reimplementing daemon startup in parallel.
+
+// OnDaemonStart starts the background services this backlog adds — the MFS
+// auto-pin loop — for as long as the daemon runs.
+func OnDaemonStart(ctx context.Context, node *core.IpfsNode) {
+	pin.StartPinMFS(ctx, pin.MFSPinPollInterval(), node)
+}
+
+// DaemonServeOptions returns the corehttp.ServeOption(s) this backlog adds,
+// for cmd/ipfs/daemon.go to append to its own option list alongside the
+// gateway, the commands API, and everything else it already mounts.
+func DaemonServeOptions() []corehttp.ServeOption {
+	return []corehttp.ServeOption{
+		pinsvc.PinningServiceOption(),
+	}
+}
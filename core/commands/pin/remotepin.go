@@ -14,6 +14,7 @@ import (
 	fsrepo "github.com/ipfs/go-ipfs/repo/fsrepo"
 	logging "github.com/ipfs/go-log"
 	pinclient "github.com/ipfs/go-pinning-service-http-client"
+	coreiface "github.com/ipfs/interface-go-ipfs-core"
 	path "github.com/ipfs/interface-go-ipfs-core/path"
 	peer "github.com/libp2p/go-libp2p-core/peer"
 	"github.com/multiformats/go-multiaddr"
@@ -30,6 +31,7 @@ var remotePinCmd = &cmds.Command{
 		"add":     addRemotePinCmd,
 		"ls":      listRemotePinCmd,
 		"rm":      rmRemotePinCmd,
+		"update":  updateRemotePinCmd,
 		"service": remotePinServiceCmd,
 	},
 }
@@ -40,9 +42,11 @@ var remotePinServiceCmd = &cmds.Command{
 	},
 
 	Subcommands: map[string]*cmds.Command{
-		"add": addRemotePinServiceCmd,
-		"ls":  lsRemotePinServiceCmd,
-		"rm":  rmRemotePinServiceCmd,
+		"add":   addRemotePinServiceCmd,
+		"ls":    lsRemotePinServiceCmd,
+		"rm":    rmRemotePinServiceCmd,
+		"token": remotePinServiceTokenCmd,
+		"stat":  remotePinServiceStatCmd,
 	},
 }
 
@@ -54,6 +58,7 @@ const pinServiceURLOptionName = "url"
 const pinServiceKeyOptionName = "key"
 const pinBackgroundOptionName = "background"
 const pinForceOptionName = "force"
+const pinServiceNoVerifyOptionName = "no-verify"
 
 type RemotePinOutput struct {
 	RequestID string
@@ -113,6 +118,10 @@ var addRemotePinCmd = &cmds.Command{
 			return err
 		}
 
+		if err := putPinPathMapping(ctx, env, ps.GetRequestId(), service, req.Arguments[0]); err != nil {
+			log.Errorf("failed to record path for remote pin %s: %s", ps.GetRequestId(), err)
+		}
+
 		for _, d := range ps.GetDelegates() {
 			p, err := peer.AddrInfoFromP2pAddr(d)
 			if err != nil {
@@ -190,7 +199,7 @@ Returns a list of objects that are pinned to a remote pinning service.
 	Arguments: []cmds.Argument{},
 	Options: []cmds.Option{
 		cmds.StringOption(pinNameOptionName, "Return pins objects with names that contain provided value (case-sensitive, exact match)."),
-		cmds.StringsOption(pinCIDsOptionName, "Return only pin objects for the specified CID(s); optional, comma separated."),
+		cmds.StringsOption(pinCIDsOptionName, "Return only pin objects for the specified CID(s) or IPFS path(s); optional, comma separated."),
 		cmds.StringsOption(pinStatusOptionName, "Return only pin objects with the specified statuses; optional, comma separated."),
 		cmds.StringOption(pinServiceNameOptionName, "Name of the remote pinning service to use."),
 	},
@@ -204,7 +213,7 @@ Returns a list of objects that are pinned to a remote pinning service.
 			return err
 		}
 
-		psCh, errCh, err := lsRemote(ctx, req, c)
+		psCh, errCh, err := lsRemote(ctx, env, req, c)
 		if err != nil {
 			return err
 		}
@@ -238,19 +247,15 @@ Returns a list of objects that are pinned to a remote pinning service.
 	},
 }
 
-func lsRemote(ctx context.Context, req *cmds.Request, c *pinclient.Client) (chan pinclient.PinStatusGetter, chan error, error) {
+func lsRemote(ctx context.Context, env cmds.Environment, req *cmds.Request, c *pinclient.Client) (chan pinclient.PinStatusGetter, chan error, error) {
 	opts := []pinclient.LsOption{}
 	if name, nameFound := req.Options[pinNameOptionName].(string); nameFound {
 		opts = append(opts, pinclient.PinOpts.FilterName(name))
 	}
 	if cidsRaw, cidsFound := req.Options[pinCIDsOptionName].([]string); cidsFound {
-		parsedCIDs := []cid.Cid{}
-		for _, rawCID := range cidsRaw {
-			parsedCID, err := cid.Decode(rawCID)
-			if err != nil {
-				return nil, nil, fmt.Errorf("CID %s cannot be parsed (%v)", rawCID, err)
-			}
-			parsedCIDs = append(parsedCIDs, parsedCID)
+		parsedCIDs, err := resolveToCIDs(ctx, env, req, cidsRaw)
+		if err != nil {
+			return nil, nil, err
 		}
 		opts = append(opts, pinclient.PinOpts.FilterCIDs(parsedCIDs...))
 	}
@@ -271,6 +276,34 @@ func lsRemote(ctx context.Context, req *cmds.Request, c *pinclient.Client) (chan
 	return psCh, errCh, nil
 }
 
+// resolveToCIDs turns a mix of raw CID strings and IPFS paths (IPNS,
+// DNSLink, MFS, ...) into concrete CIDs by resolving anything that doesn't
+// parse as a bare CID through the CoreAPI's ResolvePath.
+func resolveToCIDs(ctx context.Context, env cmds.Environment, req *cmds.Request, raw []string) ([]cid.Cid, error) {
+	out := make([]cid.Cid, 0, len(raw))
+	var api coreiface.CoreAPI
+	for _, r := range raw {
+		if c, err := cid.Decode(r); err == nil {
+			out = append(out, c)
+			continue
+		}
+
+		if api == nil {
+			var err error
+			api, err = cmdenv.GetApi(env, req)
+			if err != nil {
+				return nil, err
+			}
+		}
+		rp, err := api.ResolvePath(ctx, path.New(r))
+		if err != nil {
+			return nil, fmt.Errorf("%s is neither a valid CID nor a resolvable IPFS path (%v)", r, err)
+		}
+		out = append(out, rp.Cid())
+	}
+	return out, nil
+}
+
 var rmRemotePinCmd = &cmds.Command{
 	Helptext: cmds.HelpText{
 		Tagline: "Remove pinned objects from remote pinning service.",
@@ -302,7 +335,7 @@ collected if needed.
 
 		rmIDs := []string{}
 		if len(req.Arguments) == 0 {
-			psCh, errCh, err := lsRemote(ctx, req, c)
+			psCh, errCh, err := lsRemote(ctx, env, req, c)
 			if err != nil {
 				return err
 			}
@@ -323,6 +356,9 @@ collected if needed.
 			if err = c.DeleteByID(ctx, rmID); err != nil {
 				return fmt.Errorf("removing pin with request ID %s (%v)", rmID, err)
 			}
+			if err := deletePinPathMapping(ctx, env, rmID); err != nil {
+				log.Debugf("no path mapping to clean up for remote pin %s: %s", rmID, err)
+			}
 		}
 		return nil
 	},
@@ -340,9 +376,14 @@ var addRemotePinServiceCmd = &cmds.Command{
 		cmds.StringArg(pinServiceURLOptionName, true, false, "Service URL."),
 		cmds.StringArg(pinServiceKeyOptionName, true, false, "Service key."),
 	},
-	Options: []cmds.Option{},
-	Type:    nil,
+	Options: []cmds.Option{
+		cmds.BoolOption(pinServiceNoVerifyOptionName, "Skip the add-time connectivity/credential check (useful for offline setups)."),
+	},
+	Type: nil,
 	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx, cancel := context.WithCancel(req.Context)
+		defer cancel()
+
 		cfgRoot, err := cmdenv.GetConfigRoot(env)
 		if err != nil {
 			return err
@@ -357,7 +398,7 @@ var addRemotePinServiceCmd = &cmds.Command{
 		if !nameFound {
 			return fmt.Errorf("service name not given")
 		}
-		url, urlFound := req.Options[pinServiceURLOptionName].(string)
+		rawURL, urlFound := req.Options[pinServiceURLOptionName].(string)
 		if !urlFound {
 			return fmt.Errorf("service url not given")
 		}
@@ -366,6 +407,18 @@ var addRemotePinServiceCmd = &cmds.Command{
 			return fmt.Errorf("service key not given")
 		}
 
+		url, err := normalizeEndpoint(rawURL)
+		if err != nil {
+			return fmt.Errorf("invalid service URL: %w", err)
+		}
+
+		noVerify, _ := req.Options[pinServiceNoVerifyOptionName].(bool)
+		if !noVerify {
+			if err := probeRemotePinService(ctx, url, key); err != nil {
+				return err
+			}
+		}
+
 		cfg, err := repo.Config()
 		if err != nil {
 			return err
@@ -424,14 +477,21 @@ var rmRemotePinServiceCmd = &cmds.Command{
 	},
 }
 
+const pinServiceStatOptionName = "stat"
+
 var lsRemotePinServiceCmd = &cmds.Command{
 	Helptext: cmds.HelpText{
 		Tagline:          "List remote pinning services.",
 		ShortDescription: "List remote pinning services.",
 	},
 	Arguments: []cmds.Argument{},
-	Options:   []cmds.Option{},
+	Options: []cmds.Option{
+		cmds.BoolOption(pinServiceStatOptionName, "Include per-status pin counts for each service.").WithDefault(false),
+	},
 	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx, cancel := context.WithCancel(req.Context)
+		defer cancel()
+
 		cfgRoot, err := cmdenv.GetConfigRoot(env)
 		if err != nil {
 			return err
@@ -449,9 +509,16 @@ var lsRemotePinServiceCmd = &cmds.Command{
 		if cfg.RemotePinServices.Services == nil {
 			return nil // no pinning services added yet
 		}
+		withStat, _ := req.Options[pinServiceStatOptionName].(bool)
+
 		result := sortedServiceAndURL{}
 		for svcName, svcConfig := range cfg.RemotePinServices.Services {
-			result = append(result, PinServiceAndURL{svcName, svcConfig.URL})
+			r := PinServiceAndURL{Service: svcName, URL: svcConfig.URL}
+			if withStat {
+				stat := statRemotePinService(ctx, svcName, svcConfig)
+				r.Stat = &stat.Stat
+			}
+			result = append(result, r)
 		}
 		sort.Sort(result)
 		for _, r := range result {
@@ -467,6 +534,7 @@ var lsRemotePinServiceCmd = &cmds.Command{
 type PinServiceAndURL struct {
 	Service string
 	URL     string
+	Stat    *PinCountStat `json:",omitempty"`
 }
 
 type sortedServiceAndURL []PinServiceAndURL
@@ -487,33 +555,51 @@ func getRemotePinServiceOrEnv(env cmds.Environment, name string) (*pinclient.Cli
 	if name == "" {
 		return nil, fmt.Errorf("remote pinning service name not specified")
 	}
-	url, key, err := getRemotePinService(env, name)
+	svc, err := getRemotePinService(env, name)
 	if err != nil {
 		return nil, err
 	}
-	return pinclient.NewClient(url, key), nil
+	return pinclient.NewClient(svc.URL, bearerCredential(svc)), nil
+}
+
+// bearerCredential returns the credential to hand to pinclient.NewClient,
+// which sends it as `Authorization: Bearer <credential>`. In basic mode
+// that's the raw API key; in bearer mode it's the JWT minted by
+// `ipfs pin remote service token`.
+func bearerCredential(svc config.RemotePinService) string {
+	if svc.Auth.Mode == remotePinServiceAuthModeBearer {
+		return svc.Auth.BearerToken
+	}
+	return svc.Key
 }
 
-func getRemotePinService(env cmds.Environment, name string) (url, key string, err error) {
+func getRemotePinService(env cmds.Environment, name string) (config.RemotePinService, error) {
 	cfgRoot, err := cmdenv.GetConfigRoot(env)
 	if err != nil {
-		return "", "", err
+		return config.RemotePinService{}, err
 	}
 	repo, err := fsrepo.Open(cfgRoot)
 	if err != nil {
-		return "", "", err
+		return config.RemotePinService{}, err
 	}
 	defer repo.Close()
 	cfg, err := repo.Config()
 	if err != nil {
-		return "", "", err
+		return config.RemotePinService{}, err
 	}
 	if cfg.RemotePinServices.Services == nil {
-		return "", "", fmt.Errorf("service not known")
+		return config.RemotePinService{}, fmt.Errorf("service not known")
 	}
 	service, present := cfg.RemotePinServices.Services[name]
 	if !present {
-		return "", "", fmt.Errorf("service not known")
+		return config.RemotePinService{}, fmt.Errorf("service not known")
 	}
-	return service.URL, service.Key, nil
+
+	// Normalize on read too, for services added before normalizeEndpoint
+	// existed and stored verbatim.
+	if normalized, err := normalizeEndpoint(service.URL); err == nil {
+		service.URL = normalized
+	}
+
+	return service, nil
 }
\ No newline at end of file
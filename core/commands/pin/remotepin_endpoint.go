@@ -0,0 +1,47 @@
+package pin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	pinclient "github.com/ipfs/go-pinning-service-http-client"
+)
+
+// normalizeEndpoint canonicalizes a user-provided remote pinning service
+// URL: it requires an http(s) scheme, strips a trailing "/pins" (which
+// users sometimes copy straight out of the API docs) and any trailing
+// slash, and rejects URLs carrying query or fragment components, which
+// pinclient.NewClient would otherwise silently ignore or mishandle.
+func normalizeEndpoint(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("URL must have an http or https scheme")
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("URL must not include a query or fragment")
+	}
+
+	u.Path = strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), "/pins")
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	return u.String(), nil
+}
+
+// probeRemotePinService issues an authenticated GET /pins?limit=1 against
+// the service to catch a bad URL or bad credentials at add-time rather than
+// the first time the user runs `pin remote add`.
+func probeRemotePinService(ctx context.Context, url, key string) error {
+	c := pinclient.NewClient(url, key)
+	psCh, errCh := c.Ls(ctx, pinclient.PinOpts.Limit(1))
+	for range psCh {
+	}
+	if err := <-errCh; err != nil {
+		return fmt.Errorf("could not verify service (%w); use --no-verify to skip this check", err)
+	}
+	return nil
+}
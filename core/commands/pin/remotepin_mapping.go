@@ -0,0 +1,64 @@
+package pin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	ds "github.com/ipfs/go-datastore"
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	"github.com/ipfs/go-ipfs/core/commands/cmdenv"
+)
+
+// pinPathMappingPrefix namespaces the sidecar datastore keys used to
+// remember the original IPFS path behind a remote pin's request ID, so that
+// `ipfs pin remote update` can later re-resolve a mutable path (IPNS,
+// DNSLink, MFS) rather than being stuck with the CID it resolved to at
+// pin-time.
+const pinPathMappingPrefix = "/remotepin/path/"
+
+// pinPathMapping is what gets persisted per request ID.
+type pinPathMapping struct {
+	Path    string
+	Service string
+}
+
+func pinPathMappingKey(requestID string) ds.Key {
+	return ds.NewKey(pinPathMappingPrefix + requestID)
+}
+
+func putPinPathMapping(ctx context.Context, env cmds.Environment, requestID, service, ipfsPath string) error {
+	node, err := cmdenv.GetNode(env)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(pinPathMapping{Path: ipfsPath, Service: service})
+	if err != nil {
+		return err
+	}
+	return node.Repo.Datastore().Put(ctx, pinPathMappingKey(requestID), b)
+}
+
+func getPinPathMapping(ctx context.Context, env cmds.Environment, requestID string) (pinPathMapping, error) {
+	node, err := cmdenv.GetNode(env)
+	if err != nil {
+		return pinPathMapping{}, err
+	}
+	b, err := node.Repo.Datastore().Get(ctx, pinPathMappingKey(requestID))
+	if err != nil {
+		return pinPathMapping{}, fmt.Errorf("no path recorded for request ID %s (%w)", requestID, err)
+	}
+	var m pinPathMapping
+	if err := json.Unmarshal(b, &m); err != nil {
+		return pinPathMapping{}, err
+	}
+	return m, nil
+}
+
+func deletePinPathMapping(ctx context.Context, env cmds.Environment, requestID string) error {
+	node, err := cmdenv.GetNode(env)
+	if err != nil {
+		return err
+	}
+	return node.Repo.Datastore().Delete(ctx, pinPathMappingKey(requestID))
+}
@@ -0,0 +1,147 @@
+package pin
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	config "github.com/ipfs/go-ipfs-config"
+	"github.com/ipfs/go-ipfs/core"
+	pinclient "github.com/ipfs/go-pinning-service-http-client"
+)
+
+// mfsPinPollIntervalEnvVar overrides how often the MFS root is checked
+// against each service's repin policy. Mostly useful for testing.
+const mfsPinPollIntervalEnvVar = "MFS_PIN_POLL_INTERVAL"
+
+const defaultMfsPinPollInterval = 5 * time.Minute
+
+const defaultMfsPinName = "mfs"
+
+// mfsServiceState tracks what we last pinned to a given remote service so
+// that StartPinMFS only issues a repin when the MFS root changed or the
+// service's RepinInterval has elapsed since the last successful pin.
+type mfsServiceState struct {
+	lastCid string
+	lastPin time.Time
+}
+
+// StartPinMFS starts a background goroutine that periodically resolves the
+// node's MFS root and, for every configured remote pinning service with
+// Policies.MFS.Enable set, keeps a pin named Policies.MFS.PinName pointed at
+// it. It returns immediately; the goroutine runs until ctx is canceled.
+func StartPinMFS(ctx context.Context, pollInterval time.Duration, node *core.IpfsNode) {
+	go func() {
+		state := map[string]*mfsServiceState{}
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				pinMFSOnce(ctx, node, state)
+			}
+		}
+	}()
+}
+
+// MFSPinPollInterval reads the MFS_PIN_POLL_INTERVAL env var, falling back
+// to defaultMfsPinPollInterval. Callers starting StartPinMFS at daemon
+// onlining use this to pick pollInterval.
+func MFSPinPollInterval() time.Duration {
+	if raw := os.Getenv(mfsPinPollIntervalEnvVar); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil {
+			return d
+		}
+		log.Errorf("invalid %s value %q, using default of %s", mfsPinPollIntervalEnvVar, raw, defaultMfsPinPollInterval)
+	}
+	return defaultMfsPinPollInterval
+}
+
+// pinMFSOnce resolves the current MFS root and re-pins it to every service
+// that needs it, skipping services whose root hasn't changed and whose
+// RepinInterval hasn't elapsed. Errors talking to one service are logged and
+// do not prevent the others from being processed.
+func pinMFSOnce(ctx context.Context, node *core.IpfsNode, state map[string]*mfsServiceState) {
+	cfg, err := node.Repo.Config()
+	if err != nil {
+		log.Errorf("pin mfs: reading config: %s", err)
+		return
+	}
+
+	rootNode, err := node.FilesRoot.GetDirectory().GetNode()
+	if err != nil {
+		log.Errorf("pin mfs: resolving MFS root: %s", err)
+		return
+	}
+	rootCid := rootNode.Cid()
+
+	var wg sync.WaitGroup
+	for name, svc := range cfg.RemotePinServices.Services {
+		if !svc.Policies.MFS.Enable {
+			continue
+		}
+
+		st, ok := state[name]
+		if !ok {
+			st = &mfsServiceState{}
+			state[name] = st
+		}
+
+		repinInterval := defaultMfsPinPollInterval
+		if svc.Policies.MFS.RepinInterval != "" {
+			if d, err := time.ParseDuration(svc.Policies.MFS.RepinInterval); err == nil {
+				repinInterval = d
+			} else {
+				log.Errorf("pin mfs: service %s has invalid RepinInterval %q: %s", name, svc.Policies.MFS.RepinInterval, err)
+			}
+		}
+
+		if st.lastCid == rootCid.String() && time.Since(st.lastPin) < repinInterval {
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, svc config.RemotePinService, st *mfsServiceState) {
+			defer wg.Done()
+			if err := pinMFSToService(ctx, svc, rootCid); err != nil {
+				log.Errorf("pin mfs: service %s: %s", name, err)
+				return
+			}
+			st.lastCid = rootCid.String()
+			st.lastPin = time.Now()
+		}(name, svc, st)
+	}
+	wg.Wait()
+}
+
+func pinMFSToService(ctx context.Context, svc config.RemotePinService, rootCid cid.Cid) error {
+	pinName := svc.Policies.MFS.PinName
+	if pinName == "" {
+		pinName = defaultMfsPinName
+	}
+
+	client := pinclient.NewClient(svc.URL, bearerCredential(svc))
+
+	// Replace an existing pin of that name, if any, rather than adding
+	// another one next to it.
+	psCh, errCh := client.Ls(ctx, pinclient.PinOpts.FilterName(pinName), pinclient.PinOpts.Limit(1))
+	existingID := ""
+	for ps := range psCh {
+		existingID = ps.GetRequestId()
+	}
+	if err := <-errCh; err != nil {
+		return err
+	}
+	if existingID != "" {
+		_, err := client.Replace(ctx, existingID, rootCid, pinclient.PinOpts.WithName(pinName))
+		return err
+	}
+
+	_, err := client.Add(ctx, rootCid, pinclient.PinOpts.WithName(pinName))
+	return err
+}
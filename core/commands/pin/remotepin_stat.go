@@ -0,0 +1,172 @@
+package pin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	config "github.com/ipfs/go-ipfs-config"
+	"github.com/ipfs/go-ipfs/core/commands/cmdenv"
+	fsrepo "github.com/ipfs/go-ipfs/repo/fsrepo"
+	pinclient "github.com/ipfs/go-pinning-service-http-client"
+)
+
+const (
+	statHealthOK    = "ok"
+	statHealthError = "error"
+)
+
+var remotePinStatStatuses = []pinclient.Status{
+	pinclient.StatusQueued,
+	pinclient.StatusPinning,
+	pinclient.StatusPinned,
+	pinclient.StatusFailed,
+}
+
+// PinServiceStat reports per-status pin counts for one configured remote
+// pinning service, along with whether the service could be reached and
+// authenticated at all.
+type PinServiceStat struct {
+	Service     string
+	EndpointURL string
+	Stat        PinCountStat
+}
+
+// PinCountStat holds the counts this chunk's `stat` subcommand cares about,
+// plus a health flag for when the service couldn't be queried at all.
+type PinCountStat struct {
+	Status map[string]int
+	Health string
+	Error  string
+}
+
+var remotePinServiceStatCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline:          "Show status of remote pinning services.",
+		ShortDescription: "Query configured remote pinning service(s) for per-status pin counts.",
+	},
+	Options: []cmds.Option{
+		cmds.StringOption(pinServiceNameOptionName, "Report on a single remote pinning service."),
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx, cancel := context.WithCancel(req.Context)
+		defer cancel()
+
+		services, err := remotePinServicesToStat(env, req)
+		if err != nil {
+			return err
+		}
+
+		for name, svc := range services {
+			if err := res.Emit(statRemotePinService(ctx, name, svc)); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Type: PinServiceStat{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *PinServiceStat) error {
+			if out.Stat.Health != statHealthOK {
+				fmt.Fprintf(w, "%s.health=%s %s.error=%q\n", out.Service, out.Stat.Health, out.Service, out.Stat.Error)
+				return nil
+			}
+			for _, s := range remotePinStatStatuses {
+				fmt.Fprintf(w, "%s.%s=%d\n", out.Service, s, out.Stat.Status[string(s)])
+			}
+			return nil
+		}),
+	},
+}
+
+func remotePinServicesToStat(env cmds.Environment, req *cmds.Request) (map[string]config.RemotePinService, error) {
+	if name, nameFound := req.Options[pinServiceNameOptionName].(string); nameFound && name != "" {
+		svc, err := getRemotePinService(env, name)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]config.RemotePinService{name: svc}, nil
+	}
+
+	cfgRoot, err := cmdenv.GetConfigRoot(env)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := fsrepo.Open(cfgRoot)
+	if err != nil {
+		return nil, err
+	}
+	defer repo.Close()
+	cfg, err := repo.Config()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.RemotePinServices.Services, nil
+}
+
+// statRemotePinService queries one service for per-status pin counts by
+// streaming each status's matching pins and counting them.
+func statRemotePinService(ctx context.Context, name string, svc config.RemotePinService) *PinServiceStat {
+	c := pinclient.NewClient(svc.URL, bearerCredential(svc))
+
+	counts := map[string]int{}
+	for _, s := range remotePinStatStatuses {
+		n, err := countRemotePins(ctx, c, s)
+		if err != nil {
+			return &PinServiceStat{
+				Service:     name,
+				EndpointURL: svc.URL,
+				Stat:        PinCountStat{Health: statHealthError, Error: err.Error()},
+			}
+		}
+		counts[string(s)] = n
+	}
+
+	return &PinServiceStat{
+		Service:     name,
+		EndpointURL: svc.URL,
+		Stat:        PinCountStat{Status: counts, Health: statHealthOK},
+	}
+}
+
+// remotePinStatPageSize is the page size countRemotePins requests. Without
+// an explicit limit some services cap page size on their own and ignoring
+// that would silently undercount, so countRemotePins always pages
+// explicitly rather than relying on an unbounded Ls.
+const remotePinStatPageSize = 1000
+
+// countRemotePins counts pins matching status by paging through them with
+// an explicit limit, following the `before` cursor (the oldest `created`
+// timestamp seen in the previous page) until a page comes back short of
+// remotePinStatPageSize, which signals the last page.
+func countRemotePins(ctx context.Context, c *pinclient.Client, status pinclient.Status) (int, error) {
+	count := 0
+	var before time.Time
+	for {
+		opts := []pinclient.LsOption{pinclient.PinOpts.FilterStatus(status), pinclient.PinOpts.Limit(remotePinStatPageSize)}
+		if !before.IsZero() {
+			opts = append(opts, pinclient.PinOpts.Before(before))
+		}
+
+		psCh, errCh := c.Ls(ctx, opts...)
+		page := 0
+		var oldest time.Time
+		for ps := range psCh {
+			page++
+			count++
+			if created := ps.GetCreated(); oldest.IsZero() || created.Before(oldest) {
+				oldest = created
+			}
+		}
+		if err := <-errCh; err != nil {
+			return 0, err
+		}
+
+		if page < remotePinStatPageSize {
+			return count, nil
+		}
+		before = oldest
+	}
+}
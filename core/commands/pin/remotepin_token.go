@@ -0,0 +1,117 @@
+package pin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	"github.com/ipfs/go-ipfs/core/commands/cmdenv"
+	fsrepo "github.com/ipfs/go-ipfs/repo/fsrepo"
+)
+
+const (
+	remotePinServiceAuthModeBasic  = "basic"
+	remotePinServiceAuthModeBearer = "bearer"
+)
+
+// fetchRemotePinServiceToken bootstraps a bearer token by exchanging a
+// username/password pair with the service's POST /token endpoint (the
+// pinsvc.handler.issueToken route when the service is another go-ipfs
+// node). There's no pinclient helper for this since /token isn't part of
+// the Pinning Services API spec itself, so this makes the request
+// directly rather than going through pinclient.Client.
+func fetchRemotePinServiceToken(ctx context.Context, endpointURL, username, password string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpointURL+"/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(username, password)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("service returned %s: %s", resp.Status, body)
+	}
+
+	var out struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("decoding token response: %w", err)
+	}
+	if out.Token == "" {
+		return "", fmt.Errorf("service did not return a token")
+	}
+	return out.Token, nil
+}
+
+var remotePinServiceTokenCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline:          "Bootstrap or rotate a bearer token for a remote pinning service.",
+		ShortDescription: "Exchanges a username/password with the service's POST /token endpoint for a bearer token and stores it for use as the service's credential.",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg(pinServiceNameOptionName, true, false, "Service name."),
+		cmds.StringArg("username", true, false, "Username to sign the token as."),
+		cmds.StringArg("password", true, false, "Password to authenticate the bootstrap request with."),
+	},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		if len(req.Arguments) != 3 {
+			return fmt.Errorf("expecting service name, username, and password")
+		}
+		name, username, password := req.Arguments[0], req.Arguments[1], req.Arguments[2]
+
+		cfgRoot, err := cmdenv.GetConfigRoot(env)
+		if err != nil {
+			return err
+		}
+		repo, err := fsrepo.Open(cfgRoot)
+		if err != nil {
+			return err
+		}
+		defer repo.Close()
+
+		cfg, err := repo.Config()
+		if err != nil {
+			return err
+		}
+		svc, present := cfg.RemotePinServices.Services[name]
+		if !present {
+			return fmt.Errorf("service not known")
+		}
+
+		token, err := fetchRemotePinServiceToken(req.Context, svc.URL, username, password)
+		if err != nil {
+			return err
+		}
+		svc.Auth.Mode = remotePinServiceAuthModeBearer
+		svc.Auth.BearerToken = token
+		cfg.RemotePinServices.Services[name] = svc
+
+		if err := repo.SetConfig(cfg); err != nil {
+			return err
+		}
+
+		return res.Emit(&remotePinServiceTokenOutput{Service: name, Token: token})
+	},
+	Type: remotePinServiceTokenOutput{},
+	Encoders: cmds.EncoderMap{
+		cmds.Text: cmds.MakeTypedEncoder(func(req *cmds.Request, w io.Writer, out *remotePinServiceTokenOutput) error {
+			fmt.Fprintf(w, "%s\n", out.Token)
+			return nil
+		}),
+	},
+}
+
+type remotePinServiceTokenOutput struct {
+	Service string
+	Token   string
+}
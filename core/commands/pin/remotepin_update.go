@@ -0,0 +1,80 @@
+package pin
+
+import (
+	"context"
+	"fmt"
+
+	cmds "github.com/ipfs/go-ipfs-cmds"
+	"github.com/ipfs/go-ipfs/core/commands/cmdenv"
+	pinclient "github.com/ipfs/go-pinning-service-http-client"
+	path "github.com/ipfs/interface-go-ipfs-core/path"
+)
+
+var updateRemotePinCmd = &cmds.Command{
+	Helptext: cmds.HelpText{
+		Tagline:          "Replace a remote pin, re-resolving its original IPFS path.",
+		ShortDescription: "Re-resolves the path recorded for a remote pin and replaces the pin at its service to point at the new CID.",
+	},
+	Arguments: []cmds.Argument{
+		cmds.StringArg("request-id", true, false, "Request ID of the remote pin to update.").EnableStdin(),
+	},
+	Options: []cmds.Option{
+		cmds.StringOption(pinNameOptionName, "An optional new name for the pin."),
+	},
+	Type: RemotePinOutput{},
+	Run: func(req *cmds.Request, res cmds.ResponseEmitter, env cmds.Environment) error {
+		ctx, cancel := context.WithCancel(req.Context)
+		defer cancel()
+
+		if len(req.Arguments) != 1 {
+			return fmt.Errorf("expecting one request ID argument")
+		}
+		requestID := req.Arguments[0]
+
+		mapping, err := getPinPathMapping(ctx, env, requestID)
+		if err != nil {
+			return err
+		}
+
+		api, err := cmdenv.GetApi(env, req)
+		if err != nil {
+			return err
+		}
+		rp, err := api.ResolvePath(ctx, path.New(mapping.Path))
+		if err != nil {
+			return fmt.Errorf("re-resolving %s: %w", mapping.Path, err)
+		}
+
+		c, err := getRemotePinServiceOrEnv(env, mapping.Service)
+		if err != nil {
+			return err
+		}
+
+		opts := []pinclient.AddOption{}
+		if name, nameFound := req.Options[pinNameOptionName].(string); nameFound {
+			opts = append(opts, pinclient.PinOpts.WithName(name))
+		}
+
+		ps, err := c.Replace(ctx, requestID, rp.Cid(), opts...)
+		if err != nil {
+			return err
+		}
+
+		if err := putPinPathMapping(ctx, env, ps.GetRequestId(), mapping.Service, mapping.Path); err != nil {
+			log.Errorf("failed to record path for remote pin %s: %s", ps.GetRequestId(), err)
+		}
+		if ps.GetRequestId() != requestID {
+			if err := deletePinPathMapping(ctx, env, requestID); err != nil {
+				log.Errorf("failed to clean up stale path mapping for %s: %s", requestID, err)
+			}
+		}
+
+		return res.Emit(&RemotePinOutput{
+			RequestID: ps.GetRequestId(),
+			Name:      ps.GetPin().GetName(),
+			Delegates: multiaddrsToStrings(ps.GetDelegates()),
+			Status:    ps.GetStatus().String(),
+			Cid:       ps.GetPin().GetCid().String(),
+		})
+	},
+}
@@ -0,0 +1,467 @@
+// Package pinsvc implements a server for the IPFS Pinning Services API
+// (https://ipfs.github.io/pinning-services-api-spec/), backed by the local
+// node's pinset. This lets a go-ipfs node act as a pinning-service provider
+// for the client-side `ipfs pin remote` commands of another node.
+package pinsvc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-ipfs/core"
+	"github.com/ipfs/go-ipfs/core/coreapi"
+	logging "github.com/ipfs/go-log"
+	coreiface "github.com/ipfs/interface-go-ipfs-core"
+	"github.com/ipfs/interface-go-ipfs-core/path"
+	uuid "github.com/google/uuid"
+	jwt "github.com/golang-jwt/jwt/v4"
+	ma "github.com/multiformats/go-multiaddr"
+	manet "github.com/multiformats/go-multiaddr/net"
+)
+
+// tokenLifetime bounds how long a minted bearer token is accepted for: it
+// sets the token's exp claim when issueToken mints it. The client side
+// (`ipfs pin remote service token`) stores the token as returned and does
+// not compute exp itself.
+const tokenLifetime = 30 * 24 * time.Hour
+
+var log = logging.Logger("core/corehttp/pinsvc")
+
+// Pin mirrors the Pin object of the Pinning Services API spec.
+type Pin struct {
+	Cid     string            `json:"cid"`
+	Name    string            `json:"name,omitempty"`
+	Origins []string          `json:"origins,omitempty"`
+	Meta    map[string]string `json:"meta,omitempty"`
+}
+
+// PinStatus mirrors the PinStatus object of the Pinning Services API spec.
+type PinStatus struct {
+	RequestID string   `json:"requestid"`
+	Status    string   `json:"status"`
+	Created   string   `json:"created"`
+	Pin       Pin      `json:"pin"`
+	Delegates []string `json:"delegates"`
+}
+
+const (
+	statusPinned = "pinned"
+	statusFailed = "failed"
+)
+
+// PinningServiceOption mounts the Pinning Services API, guarded by the
+// bearer-token auth derived from API.PinningService.BasicAuthCredentials.
+//
+// If API.PinningService.ListenAddress is set, the API is served on its own
+// listener at that address instead of sharing the daemon's main API mux —
+// this lets an operator expose pinning to the world without also exposing
+// the rest of the (far more privileged) API.
+func PinningServiceOption() func(n *core.IpfsNode, l net.Listener, mux *http.ServeMux) (*http.ServeMux, error) {
+	return func(n *core.IpfsNode, l net.Listener, mux *http.ServeMux) (*http.ServeMux, error) {
+		cfg, err := n.Repo.Config()
+		if err != nil {
+			return nil, err
+		}
+		if !cfg.API.PinningService.Enabled {
+			return mux, nil
+		}
+		if len(cfg.API.PinningService.BasicAuthCredentials) == 0 {
+			return nil, fmt.Errorf("API.PinningService is enabled but API.PinningService.BasicAuthCredentials is empty; refusing to serve an unauthenticated pinning API")
+		}
+
+		api, err := coreapi.NewCoreAPI(n)
+		if err != nil {
+			return nil, err
+		}
+
+		h := newHandler(n, api, cfg.API.PinningService.BasicAuthCredentials)
+
+		if cfg.API.PinningService.ListenAddress == "" {
+			mountRoutes(mux, h)
+			return mux, nil
+		}
+
+		if err := listenAndServeOwnMux(cfg.API.PinningService.ListenAddress, h); err != nil {
+			return nil, err
+		}
+		return mux, nil
+	}
+}
+
+func mountRoutes(mux *http.ServeMux, h *handler) {
+	mux.HandleFunc("/pins", h.authenticated(h.pins))
+	mux.HandleFunc("/pins/", h.authenticated(h.pinByID))
+	mux.HandleFunc("/token", h.issueToken)
+}
+
+// listenAndServeOwnMux opens its own listener at addr (a multiaddr, e.g.
+// "/ip4/0.0.0.0/tcp/5002") and serves the Pinning Services API there in the
+// background, independent of the daemon's main API listener.
+func listenAndServeOwnMux(addr string, h *handler) error {
+	maddr, err := ma.NewMultiaddr(addr)
+	if err != nil {
+		return fmt.Errorf("invalid API.PinningService.ListenAddress %q: %w", addr, err)
+	}
+	list, err := manet.Listen(maddr)
+	if err != nil {
+		return fmt.Errorf("listening on API.PinningService.ListenAddress %q: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mountRoutes(mux, h)
+
+	go func() {
+		if err := http.Serve(manet.NetListener(list), mux); err != nil {
+			log.Errorf("pinsvc: serving on %s: %s", addr, err)
+		}
+	}()
+
+	return nil
+}
+
+// handler serves the Pinning Services API. It keeps its own requestID ->
+// PinStatus index in memory since the local pinset (github.com/ipfs/go-ipfs/pin.Pinner)
+// only tracks CIDs, not the request IDs, names, and origins that the spec
+// requires.
+type handler struct {
+	n     *core.IpfsNode
+	api   coreiface.CoreAPI
+	creds map[string]string // username -> password, from API.PinningService.BasicAuthCredentials
+
+	mu   sync.Mutex
+	pins map[string]PinStatus
+}
+
+func newHandler(n *core.IpfsNode, api coreiface.CoreAPI, creds map[string]string) *handler {
+	return &handler{n: n, api: api, creds: creds, pins: map[string]PinStatus{}}
+}
+
+// authenticated requires a valid `Authorization: Bearer <jwt>` header, where
+// the JWT's issuer is a known username and its signature verifies against
+// that user's password. When no credentials are configured, requests are
+// passed through without any check.
+func (h *handler) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(h.creds) > 0 {
+			auth := r.Header.Get("Authorization")
+			token := strings.TrimPrefix(auth, "Bearer ")
+			if token == auth {
+				http.Error(w, "missing bearer token", http.StatusUnauthorized)
+				return
+			}
+			if _, err := h.verifyToken(token); err != nil {
+				http.Error(w, fmt.Sprintf("invalid bearer token: %s", err), http.StatusUnauthorized)
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// verifyToken checks the JWT's signature against the claimed issuer's
+// configured password and rejects expired tokens.
+func (h *handler) verifyToken(tokenString string) (issuer string, err error) {
+	claims := &jwt.RegisteredClaims{}
+	_, err = jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		password, present := h.creds[claims.Issuer]
+		if !present {
+			return nil, fmt.Errorf("unknown issuer %q", claims.Issuer)
+		}
+		return []byte(password), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return claims.Issuer, nil
+}
+
+// issueToken implements the POST /token bootstrap flow: given a
+// username/password via HTTP basic auth, mint an HMAC-signed JWT the client
+// can use as a bearer token for subsequent requests.
+func (h *handler) issueToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		http.Error(w, "missing basic auth credentials", http.StatusUnauthorized)
+		return
+	}
+	if configured, present := h.creds[username]; !present || configured != password {
+		http.Error(w, "invalid credentials", http.StatusUnauthorized)
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		Issuer:    username,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(tokenLifetime)),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(password))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, struct {
+		Token string `json:"token"`
+	}{token})
+}
+
+func (h *handler) pins(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listPins(w, r)
+	case http.MethodPost:
+		h.addPin(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *handler) pinByID(w http.ResponseWriter, r *http.Request) {
+	requestID := strings.TrimPrefix(r.URL.Path, "/pins/")
+	if requestID == "" {
+		http.Error(w, "missing requestid", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getPin(w, r, requestID)
+	case http.MethodDelete:
+		h.deletePin(w, r, requestID)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// matchMode values per the Pinning Services API spec's `match` query param.
+const (
+	matchExact    = "exact"
+	matchIexact   = "iexact"
+	matchPartial  = "partial"
+	matchIpartial = "ipartial"
+)
+
+// defaultListLimit is the `limit` used when a GET /pins request doesn't
+// specify one, per the Pinning Services API spec.
+const defaultListLimit = 10
+
+// listPins returns pins newest-first, applying status/cid/name/match
+// filters plus the spec's limit/before/after pagination: before/after
+// bound the `created` timestamp, and limit caps how many of the matching,
+// sorted results are returned. count always reports the total number of
+// matching pins, not just the page returned.
+func (h *handler) listPins(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	nameFilter := q.Get("name")
+	matchMode := q.Get("match")
+	if matchMode == "" {
+		matchMode = matchExact
+	}
+	cidFilter := splitCSV(q.Get("cid"))
+	statusFilter := splitCSV(q.Get("status"))
+
+	limit := defaultListLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	var before, after time.Time
+	var err error
+	if v := q.Get("before"); v != "" {
+		if before, err = time.Parse(time.RFC3339, v); err != nil {
+			http.Error(w, fmt.Sprintf("invalid before: %s", err), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := q.Get("after"); v != "" {
+		if after, err = time.Parse(time.RFC3339, v); err != nil {
+			http.Error(w, fmt.Sprintf("invalid after: %s", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	h.mu.Lock()
+	matched := make([]PinStatus, 0, len(h.pins))
+	for _, ps := range h.pins {
+		if nameFilter != "" && !matchName(matchMode, nameFilter, ps.Pin.Name) {
+			continue
+		}
+		if len(cidFilter) > 0 && !containsString(cidFilter, ps.Pin.Cid) {
+			continue
+		}
+		if len(statusFilter) > 0 && !containsString(statusFilter, ps.Status) {
+			continue
+		}
+		if !before.IsZero() || !after.IsZero() {
+			created, err := time.Parse(time.RFC3339, ps.Created)
+			if err != nil {
+				continue
+			}
+			if !before.IsZero() && !created.Before(before) {
+				continue
+			}
+			if !after.IsZero() && !created.After(after) {
+				continue
+			}
+		}
+		matched = append(matched, ps)
+	}
+	h.mu.Unlock()
+
+	// Created is always formatted with time.RFC3339 in UTC, which sorts
+	// lexically the same as chronologically.
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Created > matched[j].Created })
+
+	count := len(matched)
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+
+	writeJSON(w, struct {
+		Count   int         `json:"count"`
+		Results []PinStatus `json:"results"`
+	}{count, matched})
+}
+
+func (h *handler) addPin(w http.ResponseWriter, r *http.Request) {
+	var p Pin
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c, err := cid.Decode(p.Cid)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid cid: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	ps := PinStatus{
+		RequestID: uuid.New().String(),
+		Status:    statusPinned,
+		Created:   time.Now().UTC().Format(time.RFC3339),
+		Pin:       p,
+		Delegates: h.delegates(),
+	}
+
+	if err := h.api.Pin().Add(r.Context(), path.IpfsPath(c)); err != nil {
+		ps.Status = statusFailed
+		writeJSON(w, ps)
+		return
+	}
+
+	h.mu.Lock()
+	h.pins[ps.RequestID] = ps
+	h.mu.Unlock()
+
+	writeJSON(w, ps)
+}
+
+func (h *handler) getPin(w http.ResponseWriter, r *http.Request, requestID string) {
+	h.mu.Lock()
+	ps, ok := h.pins[requestID]
+	h.mu.Unlock()
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, ps)
+}
+
+func (h *handler) deletePin(w http.ResponseWriter, r *http.Request, requestID string) {
+	h.mu.Lock()
+	ps, ok := h.pins[requestID]
+	if ok {
+		delete(h.pins, requestID)
+	}
+	h.mu.Unlock()
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	c, err := cid.Decode(ps.Pin.Cid)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := h.api.Pin().Rm(r.Context(), path.IpfsPath(c)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *handler) delegates() []string {
+	addrs, err := h.n.PeerHost.Network().InterfaceListenAddresses()
+	if err != nil {
+		log.Errorf("pinsvc: listing delegate addresses: %s", err)
+		return nil
+	}
+	out := make([]string, 0, len(addrs))
+	for _, a := range addrs {
+		out = append(out, fmt.Sprintf("%s/p2p/%s", a, h.n.Identity))
+	}
+	return out
+}
+
+// matchName implements the Pinning Services API's `match` query parameter:
+// exact/iexact compare the whole name, partial/ipartial look for a
+// substring; the "i" variants are case-insensitive.
+func matchName(mode, filter, name string) bool {
+	switch mode {
+	case matchIexact:
+		return strings.EqualFold(name, filter)
+	case matchPartial:
+		return strings.Contains(name, filter)
+	case matchIpartial:
+		return strings.Contains(strings.ToLower(name), strings.ToLower(filter))
+	default: // matchExact
+		return name == filter
+	}
+}
+
+func splitCSV(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+func containsString(hay []string, needle string) bool {
+	for _, h := range hay {
+		if h == needle {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Errorf("pinsvc: encoding response: %s", err)
+	}
+}
@@ -0,0 +1,131 @@
+package pinsvc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ipfs/go-ipfs/core"
+	pinclient "github.com/ipfs/go-pinning-service-http-client"
+	jwt "github.com/golang-jwt/jwt/v4"
+)
+
+// newTestHandler returns a handler wired to a fresh in-memory node, without
+// going through the config-driven PinningServiceOption wiring.
+func newTestHandler(t *testing.T) *handler {
+	t.Helper()
+	n := newTestNode(t)
+	api := newTestAPI(t, n)
+	return newHandler(n, api, nil) // no credentials configured: auth disabled
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, *core.IpfsNode) {
+	t.Helper()
+	h := newTestHandler(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pins", h.authenticated(h.pins))
+	mux.HandleFunc("/pins/", h.authenticated(h.pinByID))
+	return httptest.NewServer(mux), h.n
+}
+
+func TestTokenAuth(t *testing.T) {
+	n := newTestNode(t)
+	api := newTestAPI(t, n)
+	h := newHandler(n, api, map[string]string{"alice": "hunter2"})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pins", h.authenticated(h.pins))
+	mux.HandleFunc("/token", h.issueToken)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/token", nil)
+	req.SetBasicAuth("alice", "wrong-password")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("token request: %s", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for wrong password, got %d", resp.StatusCode)
+	}
+
+	claims := jwt.RegisteredClaims{Issuer: "alice"}
+	tok := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := tok.SignedString([]byte("hunter2"))
+	if err != nil {
+		t.Fatalf("signing token: %s", err)
+	}
+	if _, err := h.verifyToken(signed); err != nil {
+		t.Fatalf("expected token signed with correct password to verify, got: %s", err)
+	}
+
+	badTok := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	badSigned, err := badTok.SignedString([]byte("wrong-password"))
+	if err != nil {
+		t.Fatalf("signing bad token: %s", err)
+	}
+	if _, err := h.verifyToken(badSigned); err == nil {
+		t.Fatalf("expected token signed with wrong password to fail verification")
+	}
+}
+
+func TestPinRoundTrip(t *testing.T) {
+	srv, n := newTestServer(t)
+	defer srv.Close()
+
+	client := pinclient.NewClient(srv.URL, "")
+	ctx := context.Background()
+
+	c := testCid(t, n, "hello world")
+
+	ps, err := client.Add(ctx, c, pinclient.PinOpts.WithName("test-pin"))
+	if err != nil {
+		t.Fatalf("add: %s", err)
+	}
+	if ps.GetStatus() != pinclient.StatusPinned {
+		t.Fatalf("expected pinned, got %s", ps.GetStatus())
+	}
+
+	got, err := client.GetStatusByID(ctx, ps.GetRequestId())
+	if err != nil {
+		t.Fatalf("get: %s", err)
+	}
+	if got.GetPin().GetCid() != c {
+		t.Fatalf("expected cid %s, got %s", c, got.GetPin().GetCid())
+	}
+
+	if err := client.DeleteByID(ctx, ps.GetRequestId()); err != nil {
+		t.Fatalf("delete: %s", err)
+	}
+
+	if _, err := client.GetStatusByID(ctx, ps.GetRequestId()); err == nil {
+		t.Fatalf("expected error getting deleted pin")
+	}
+}
+
+func TestPinListFilters(t *testing.T) {
+	srv, n := newTestServer(t)
+	defer srv.Close()
+
+	client := pinclient.NewClient(srv.URL, "")
+	ctx := context.Background()
+
+	if _, err := client.Add(ctx, testCid(t, n, "a"), pinclient.PinOpts.WithName("alpha")); err != nil {
+		t.Fatalf("add alpha: %s", err)
+	}
+	if _, err := client.Add(ctx, testCid(t, n, "b"), pinclient.PinOpts.WithName("beta")); err != nil {
+		t.Fatalf("add beta: %s", err)
+	}
+
+	psCh, errCh := client.Ls(ctx, pinclient.PinOpts.FilterName("alpha"))
+	count := 0
+	for range psCh {
+		count++
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("ls: %s", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 result filtered by name, got %d", count)
+	}
+}
@@ -0,0 +1,42 @@
+package pinsvc
+
+import (
+	"context"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-ipfs/core"
+	"github.com/ipfs/go-ipfs/core/coreapi"
+	coremock "github.com/ipfs/go-ipfs/core/mock"
+	"github.com/ipfs/go-merkledag"
+	coreiface "github.com/ipfs/interface-go-ipfs-core"
+)
+
+func newTestNode(t *testing.T) *core.IpfsNode {
+	t.Helper()
+	n, err := coremock.NewMockNode()
+	if err != nil {
+		t.Fatalf("creating mock node: %s", err)
+	}
+	return n
+}
+
+func newTestAPI(t *testing.T, n *core.IpfsNode) coreiface.CoreAPI {
+	t.Helper()
+	api, err := coreapi.NewCoreAPI(n)
+	if err != nil {
+		t.Fatalf("creating core api: %s", err)
+	}
+	return api
+}
+
+// testCid stores data in n as a raw block and returns its CID, so that
+// pinning the CID finds the block locally instead of fetching it.
+func testCid(t *testing.T, n *core.IpfsNode, data string) cid.Cid {
+	t.Helper()
+	nd := merkledag.NewRawNode([]byte(data))
+	if err := n.DAG.Add(context.Background(), nd); err != nil {
+		t.Fatalf("storing test data: %s", err)
+	}
+	return nd.Cid()
+}